@@ -0,0 +1,140 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/BurntSushi/toml"
+    bolt "go.etcd.io/bbolt"
+)
+
+const configPath = "config.toml"
+const guildStorePath = "guilds.db"
+const guildTranslationsBucket = "guild_translations"
+const channelProgressBucket = "channel_progress"
+const readingPlansBucket = "reading_plans"
+
+type TranslationConfig struct {
+    Name string `toml:"name"`
+    Path string `toml:"path"`
+}
+
+type Config struct {
+    Token              string              `toml:"token"`
+    Prefix             string              `toml:"prefix"`
+    DefaultTranslation string              `toml:"default_translation"`
+    Translations       []TranslationConfig `toml:"translation"`
+}
+
+func loadConfig() Config {
+    var cfg Config
+    if _, err := toml.DecodeFile(configPath, &cfg); err != nil {
+        fmt.Printf("Error loading %s: %v\n", configPath, err)
+        os.Exit(1)
+    }
+
+    if cfg.Token == "" {
+        fmt.Println("token not set in config.toml")
+        os.Exit(1)
+    }
+    if len(cfg.Translations) == 0 {
+        fmt.Println("no translations configured in config.toml")
+        os.Exit(1)
+    }
+    if cfg.DefaultTranslation == "" {
+        cfg.DefaultTranslation = cfg.Translations[0].Name
+    }
+
+    return cfg
+}
+
+// loadTranslations loads every configured translation's Bible data,
+// keyed by translation name, e.g. translations["NET"].
+func loadTranslations(cfg Config) (map[string][]BibleBook, error) {
+    result := make(map[string][]BibleBook, len(cfg.Translations))
+    for _, t := range cfg.Translations {
+        books, err := loadBibleData(t.Path)
+        if err != nil {
+            return nil, fmt.Errorf("failed to load translation %s: %v", t.Name, err)
+        }
+        result[t.Name] = books
+    }
+    return result, nil
+}
+
+// openGuildStore opens the BoltDB file used to persist per-guild
+// translation overrides, creating the bucket if it doesn't exist.
+func openGuildStore(path string) (*bolt.DB, error) {
+    db, err := bolt.Open(path, 0600, nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open guild store: %v", err)
+    }
+
+    err = db.Update(func(tx *bolt.Tx) error {
+        buckets := []string{guildTranslationsBucket, channelProgressBucket, readingPlansBucket}
+        for _, name := range buckets {
+            if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        db.Close()
+        return nil, fmt.Errorf("failed to init guild store: %v", err)
+    }
+
+    return db, nil
+}
+
+// guildTranslation returns the guild's overridden translation name, or
+// defaultTranslation if the guild has no override.
+func guildTranslation(db *bolt.DB, guildID, defaultTranslation string) string {
+    var name string
+    err := db.View(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket([]byte(guildTranslationsBucket))
+        if value := bucket.Get([]byte(guildID)); value != nil {
+            name = string(value)
+        }
+        return nil
+    })
+    if err != nil || name == "" {
+        return defaultTranslation
+    }
+    return name
+}
+
+func setGuildTranslation(db *bolt.DB, guildID, translation string) error {
+    return db.Update(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket([]byte(guildTranslationsBucket))
+        return bucket.Put([]byte(guildID), []byte(translation))
+    })
+}
+
+func progressKey(guildID, bookName string) []byte {
+    return []byte(guildID + "/" + bookName)
+}
+
+// channelProgress returns the number of messages already posted to the
+// given book's channel for a guild, so population can resume from there.
+func channelProgress(db *bolt.DB, guildID, bookName string) int {
+    var posted int
+    err := db.View(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket([]byte(channelProgressBucket))
+        if value := bucket.Get(progressKey(guildID, bookName)); value != nil {
+            fmt.Sscanf(string(value), "%d", &posted)
+        }
+        return nil
+    })
+    if err != nil {
+        return 0
+    }
+    return posted
+}
+
+func setChannelProgress(db *bolt.DB, guildID, bookName string, posted int) error {
+    return db.Update(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket([]byte(channelProgressBucket))
+        return bucket.Put(progressKey(guildID, bookName), []byte(fmt.Sprintf("%d", posted)))
+    })
+}
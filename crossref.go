@@ -0,0 +1,198 @@
+package main
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+    "sync"
+
+    "github.com/bwmarrin/discordgo"
+)
+
+// bookCategories groups each book under the category its channel should
+// be nested in, mirroring the traditional Protestant canon divisions.
+var bookCategories = map[string]string{
+    "Genesis": "Pentateuch", "Exodus": "Pentateuch", "Leviticus": "Pentateuch",
+    "Numbers": "Pentateuch", "Deuteronomy": "Pentateuch",
+
+    "Joshua": "Historical", "Judges": "Historical", "Ruth": "Historical",
+    "1 Samuel": "Historical", "2 Samuel": "Historical", "1 Kings": "Historical", "2 Kings": "Historical",
+    "1 Chronicles": "Historical", "2 Chronicles": "Historical", "Ezra": "Historical",
+    "Nehemiah": "Historical", "Esther": "Historical",
+
+    "Job": "Wisdom", "Psalms": "Wisdom", "Proverbs": "Wisdom",
+    "Ecclesiastes": "Wisdom", "Song of Solomon": "Wisdom",
+
+    "Isaiah": "Major Prophets", "Jeremiah": "Major Prophets", "Lamentations": "Major Prophets",
+    "Ezekiel": "Major Prophets", "Daniel": "Major Prophets",
+
+    "Hosea": "Minor Prophets", "Joel": "Minor Prophets", "Amos": "Minor Prophets",
+    "Obadiah": "Minor Prophets", "Jonah": "Minor Prophets", "Micah": "Minor Prophets",
+    "Nahum": "Minor Prophets", "Habakkuk": "Minor Prophets", "Zephaniah": "Minor Prophets",
+    "Haggai": "Minor Prophets", "Zechariah": "Minor Prophets", "Malachi": "Minor Prophets",
+
+    "Matthew": "Gospels", "Mark": "Gospels", "Luke": "Gospels", "John": "Gospels",
+
+    "Acts": "Epistles", "Romans": "Epistles", "1 Corinthians": "Epistles", "2 Corinthians": "Epistles",
+    "Galatians": "Epistles", "Ephesians": "Epistles", "Philippians": "Epistles", "Colossians": "Epistles",
+    "1 Thessalonians": "Epistles", "2 Thessalonians": "Epistles", "1 Timothy": "Epistles",
+    "2 Timothy": "Epistles", "Titus": "Epistles", "Philemon": "Epistles", "Hebrews": "Epistles",
+    "James": "Epistles", "1 Peter": "Epistles", "2 Peter": "Epistles", "1 John": "Epistles",
+    "2 John": "Epistles", "3 John": "Epistles", "Jude": "Epistles",
+
+    "Revelation": "Revelation",
+}
+
+const defaultCategory = "Other"
+
+func bookCategory(bookName string) string {
+    if category, ok := bookCategories[bookName]; ok {
+        return category
+    }
+    return defaultCategory
+}
+
+// bookAbbreviations maps the common abbreviation used in in-text
+// references (e.g. "Gen 1:1") to the canonical book name used as a
+// BibleBook.Name, so cross-references can be resolved to a channel.
+var bookAbbreviations = map[string]string{
+    "gen": "Genesis", "exod": "Exodus", "lev": "Leviticus", "num": "Numbers", "deut": "Deuteronomy",
+    "josh": "Joshua", "judg": "Judges", "ruth": "Ruth", "1sam": "1 Samuel", "2sam": "2 Samuel",
+    "1kgs": "1 Kings", "2kgs": "2 Kings", "1chr": "1 Chronicles", "2chr": "2 Chronicles",
+    "ezra": "Ezra", "neh": "Nehemiah", "esth": "Esther", "job": "Job", "ps": "Psalms", "psa": "Psalms",
+    "prov": "Proverbs", "eccl": "Ecclesiastes", "song": "Song of Solomon",
+    "isa": "Isaiah", "jer": "Jeremiah", "lam": "Lamentations", "ezek": "Ezekiel", "dan": "Daniel",
+    "hos": "Hosea", "joel": "Joel", "amos": "Amos", "obad": "Obadiah", "jonah": "Jonah",
+    "mic": "Micah", "nah": "Nahum", "hab": "Habakkuk", "zeph": "Zephaniah", "hag": "Haggai",
+    "zech": "Zechariah", "mal": "Malachi",
+    "matt": "Matthew", "mark": "Mark", "luke": "Luke", "john": "John", "acts": "Acts",
+    "rom": "Romans", "1cor": "1 Corinthians", "2cor": "2 Corinthians", "gal": "Galatians",
+    "eph": "Ephesians", "phil": "Philippians", "col": "Colossians",
+    "1thess": "1 Thessalonians", "2thess": "2 Thessalonians",
+    "1tim": "1 Timothy", "2tim": "2 Timothy", "titus": "Titus", "phlm": "Philemon",
+    "heb": "Hebrews", "jas": "James", "1pet": "1 Peter", "2pet": "2 Peter",
+    "1john": "1 John", "2john": "2 John", "3john": "3 John", "jude": "Jude", "rev": "Revelation",
+}
+
+// verseRefPattern matches in-text references like "Gen 1:1" or
+// "1 John 3:16". The whitespace is restricted to [^\S\n] (space/tab,
+// not newline) so it can't span the line break between one verse's
+// text and the next verse's leading "chapter:verse ", which would
+// otherwise misparse e.g. "...his brother James\n5:2 ..." as a
+// cross-reference to James 5:2.
+var verseRefPattern = regexp.MustCompile(`\b([1-3][^\S\n]?[A-Za-z]+|[A-Za-z]+)\.?[^\S\n]+(\d+):(\d+)\b`)
+
+type verseLocation struct {
+    channelID string
+    messageID string
+}
+
+// sentMessage is a message posted during the initial population pass,
+// kept around so the second pass can rewrite it with resolved links.
+type sentMessage struct {
+    bookName  string
+    channelID string
+    messageID string
+    content   string
+}
+
+// crossRefTracker collects verse locations and sent messages across the
+// concurrent workers in setupServer, then rewrites cross-references once
+// every book has been posted.
+type crossRefTracker struct {
+    mu        sync.Mutex
+    locations map[string]verseLocation
+    channels  map[string]string // bookName -> channelID, for the page-beyond-one fallback
+    sent      []sentMessage
+}
+
+func newCrossRefTracker() *crossRefTracker {
+    return &crossRefTracker{
+        locations: make(map[string]verseLocation),
+        channels:  make(map[string]string),
+    }
+}
+
+var lineVersePattern = regexp.MustCompile(`^(\d+):(\d+)\s`)
+
+func (t *crossRefTracker) recordMessage(bookName, channelID, messageID, content string) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    t.sent = append(t.sent, sentMessage{bookName: bookName, channelID: channelID, messageID: messageID, content: content})
+    t.channels[bookName] = channelID
+    for _, line := range strings.Split(content, "\n") {
+        match := lineVersePattern.FindStringSubmatch(line)
+        if match == nil {
+            continue
+        }
+        key := bookName + " " + match[1] + ":" + match[2]
+        t.locations[key] = verseLocation{channelID: channelID, messageID: messageID}
+    }
+}
+
+// resolveBookName normalizes an in-text reference token (e.g. "Gen",
+// "1 John", "john") to the canonical book name, or "" if unrecognized.
+func resolveBookName(token string) string {
+    normalized := strings.ToLower(strings.ReplaceAll(token, " ", ""))
+    normalized = strings.TrimSuffix(normalized, ".")
+    if name, ok := bookAbbreviations[normalized]; ok {
+        return name
+    }
+    for name := range bookCategories {
+        if strings.ToLower(strings.ReplaceAll(name, " ", "")) == normalized {
+            return name
+        }
+    }
+    return ""
+}
+
+// linkCrossReferences runs the second pass: for every book's page-one
+// message posted in the first pass, any recognized verse reference is
+// rewritten into a Discord message link. A reference whose verse lands
+// on page one of its target book links straight to that message; pages
+// beyond one are never their own message (they only materialize when a
+// reader clicks Next), so those references fall back to a link on the
+// target book's channel itself rather than being left as plain text.
+func linkCrossReferences(s *discordgo.Session, guildID string, books []BibleBook, tracker *crossRefTracker) {
+    for _, msg := range tracker.sent {
+        linked := verseRefPattern.ReplaceAllStringFunc(msg.content, func(ref string) string {
+            parts := verseRefPattern.FindStringSubmatch(ref)
+            bookName := resolveBookName(parts[1])
+            if bookName == "" {
+                return ref
+            }
+            key := fmt.Sprintf("%s %s:%s", bookName, parts[2], parts[3])
+            if loc, ok := tracker.locations[key]; ok {
+                return fmt.Sprintf("[%s](https://discord.com/channels/%s/%s/%s)", ref, guildID, loc.channelID, loc.messageID)
+            }
+            if channelID, ok := tracker.channels[bookName]; ok {
+                return fmt.Sprintf("[%s](https://discord.com/channels/%s/%s)", ref, guildID, channelID)
+            }
+            return ref
+        })
+
+        if linked == msg.content {
+            continue
+        }
+
+        book, ok := findBook(books, msg.bookName)
+        if !ok {
+            continue
+        }
+        pages := bookPages(book)
+        pages[0] = linked
+
+        embeds := []*discordgo.MessageEmbed{bookPageEmbed(book, pages, 0)}
+        components := bookPageComponents(book.Name, 0, len(pages))
+        edit := &discordgo.MessageEdit{
+            Channel:    msg.channelID,
+            ID:         msg.messageID,
+            Embeds:     embeds,
+            Components: components,
+        }
+        if _, err := s.ChannelMessageEditComplex(edit); err != nil {
+            fmt.Printf("Warning: failed to link cross-references in message %s: %v\n", msg.messageID, err)
+        }
+    }
+}
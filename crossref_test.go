@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestResolveBookName(t *testing.T) {
+    cases := map[string]string{
+        "Gen":    "Genesis",
+        "gen":    "Genesis",
+        "John":   "John",
+        "1 John": "1 John",
+        "1John":  "1 John",
+        "Obad.":  "Obadiah",
+        "nope":   "",
+    }
+    for in, want := range cases {
+        if got := resolveBookName(in); got != want {
+            t.Errorf("resolveBookName(%q) = %q, want %q", in, got, want)
+        }
+    }
+}
+
+func TestVerseRefPatternMatches(t *testing.T) {
+    text := "See Gen 1:1 and 1 John 3:16 for context."
+    got := verseRefPattern.FindAllString(text, -1)
+    want := []string{"Gen 1:1", "1 John 3:16"}
+    if len(got) != len(want) {
+        t.Fatalf("got matches %v, want %v", got, want)
+    }
+    for i, m := range got {
+        if m != want[i] {
+            t.Errorf("match %d = %q, want %q", i, m, want[i])
+        }
+    }
+}
+
+func TestBookCategoryFallsBackToOtherForUnknownBook(t *testing.T) {
+    if got := bookCategory("Enoch"); got != defaultCategory {
+        t.Errorf("bookCategory(%q) = %q, want %q", "Enoch", got, defaultCategory)
+    }
+}
+
+func TestVerseRefPatternDoesNotCrossLineBreaks(t *testing.T) {
+    text := "13:55 ...and his brother James\n5:2 Blessed are they..."
+    if got := verseRefPattern.FindAllString(text, -1); got != nil {
+        t.Errorf("verseRefPattern should not match across a newline, got %v", got)
+    }
+}
@@ -2,13 +2,16 @@ package main
 
 import (
     "encoding/json"
+    "errors"
     "fmt"
     "io/ioutil"
-    "os"
+    "math/rand"
     "strings"
+    "sync"
+    "time"
 
     "github.com/bwmarrin/discordgo"
-    "github.com/joho/godotenv"
+    bolt "go.etcd.io/bbolt"
 )
 
 type Verse struct {
@@ -31,33 +34,55 @@ type BibleBook struct {
     Verses []Verse
 }
 
-type Config struct {
-    Token string
-}
-
-func loadConfig() Config {
-    if err := godotenv.Load(); err != nil {
-        fmt.Println("Error loading .env file")
-        os.Exit(1)
+// translations holds the loaded Bible data for every configured
+// translation, keyed by translation name (e.g. "NET", "KJV").
+var translations map[string][]BibleBook
+
+// searchIndexes holds a search index per translation, built at startup
+// so /search can answer in O(hits) instead of scanning every verse.
+var searchIndexes map[string]map[string][]*Verse
+
+// defaultTranslation is used when a guild has no stored override.
+var defaultTranslation string
+
+// guildStore persists per-guild translation overrides.
+var guildStore *bolt.DB
+
+func buildSearchIndex(books []BibleBook) map[string][]*Verse {
+    index := make(map[string][]*Verse)
+    for i := range books {
+        for j := range books[i].Verses {
+            verse := &books[i].Verses[j]
+            for _, word := range strings.Fields(verse.Text) {
+                word = strings.ToLower(strings.Trim(word, ".,;:!?\"'()"))
+                if word == "" {
+                    continue
+                }
+                index[word] = append(index[word], verse)
+            }
+        }
     }
+    return index
+}
 
-    token := os.Getenv("DISCORD_BOT_TOKEN")
-    if token == "" {
-        fmt.Println("DISCORD_BOT_TOKEN environment variable not set")
-        os.Exit(1)
+func findBook(books []BibleBook, name string) (BibleBook, bool) {
+    for _, book := range books {
+        if strings.EqualFold(book.Name, name) {
+            return book, true
+        }
     }
-    return Config{Token: token}
+    return BibleBook{}, false
 }
 
-func loadBibleData() ([]BibleBook, error) {
-    file, err := ioutil.ReadFile("net.json")
+func loadBibleData(path string) ([]BibleBook, error) {
+    file, err := ioutil.ReadFile(path)
     if err != nil {
-        return nil, fmt.Errorf("failed to read net.json: %v", err)
+        return nil, fmt.Errorf("failed to read %s: %v", path, err)
     }
 
     var bibleData BibleData
     if err := json.Unmarshal(file, &bibleData); err != nil {
-        return nil, fmt.Errorf("failed to parse net.json: %v", err)
+        return nil, fmt.Errorf("failed to parse %s: %v", path, err)
     }
 
     bookMap := make(map[string][]Verse)
@@ -84,24 +109,11 @@ func loadBibleData() ([]BibleBook, error) {
     return books, nil
 }
 
-func splitMessage(text string) []string {
-    const maxLength = 1000
-    var messages []string
-    for len(text) > 0 {
-        if len(text) <= maxLength {
-            messages = append(messages, text)
-            break
-        }
+// messageContentLimit is Discord's real per-message content cap.
+const messageContentLimit = 2000
 
-        // Find the last newline before maxLength
-        lastNewline := strings.LastIndex(text[:maxLength], "\n")
-        if lastNewline == -1 {
-            lastNewline = maxLength
-        }
-        messages = append(messages, text[:lastNewline])
-        text = text[lastNewline:]
-    }
-    return messages
+func splitMessage(text string) []string {
+    return splitTextByLines(text, messageContentLimit)
 }
 
 func formatBook(book BibleBook) string {
@@ -114,6 +126,10 @@ func formatBook(book BibleBook) string {
     return builder.String()
 }
 
+// setupWorkerCount bounds how many book channels are populated in
+// parallel, keeping well under Discord's 50/sec global rate limit.
+const setupWorkerCount = 4
+
 func setupServer(s *discordgo.Session, guildID string, books []BibleBook) error {
     guild, err := s.Guild(guildID)
     if err != nil {
@@ -132,16 +148,92 @@ func setupServer(s *discordgo.Session, guildID string, books []BibleBook) error
         return fmt.Errorf("could not find @everyone role")
     }
 
+    existingChannels, err := s.GuildChannels(guildID)
+    if err != nil {
+        return fmt.Errorf("failed to list existing channels: %v", err)
+    }
+    existingByName := make(map[string]*discordgo.Channel, len(existingChannels))
+    for _, channel := range existingChannels {
+        existingByName[channel.Name] = channel
+    }
+
+    categories, err := ensureCategories(s, guildID, books, existingByName)
+    if err != nil {
+        return fmt.Errorf("failed to set up categories: %v", err)
+    }
+
+    tracker := newCrossRefTracker()
+
+    jobs := make(chan BibleBook)
+    var wg sync.WaitGroup
+    for i := 0; i < setupWorkerCount; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for book := range jobs {
+                parentID := categories[bookCategory(book.Name)]
+                if err := populateBookChannel(s, guildID, book, everyoneRoleID, parentID, existingByName, tracker); err != nil {
+                    fmt.Printf("Warning: failed to populate %s: %v\n", book.Name, err)
+                }
+            }
+        }()
+    }
     for _, book := range books {
-        channelName := strings.ToLower(strings.ReplaceAll(book.Name, " ", "-"))
-        if len(channelName) > 100 {
-            channelName = channelName[:100]
+        jobs <- book
+    }
+    close(jobs)
+    wg.Wait()
+
+    linkCrossReferences(s, guildID, books, tracker)
+
+    return nil
+}
+
+// ensureCategories creates (or reuses) a Discord category channel for
+// every category used by books, returning a map of category name to
+// channel ID for use as a book channel's ParentID.
+func ensureCategories(s *discordgo.Session, guildID string, books []BibleBook, existingByName map[string]*discordgo.Channel) (map[string]string, error) {
+    needed := make(map[string]bool)
+    for _, book := range books {
+        needed[bookCategory(book.Name)] = true
+    }
+
+    categoryIDs := make(map[string]string, len(needed))
+    for name := range needed {
+        if existing, ok := existingByName[name]; ok && existing.Type == discordgo.ChannelTypeGuildCategory {
+            categoryIDs[name] = existing.ID
+            continue
         }
 
-        channel, err := s.GuildChannelCreate(guildID, channelName, discordgo.ChannelTypeGuildText)
+        category, err := s.GuildChannelCreate(guildID, name, discordgo.ChannelTypeGuildCategory)
         if err != nil {
-            fmt.Printf("Warning: failed to create channel for %s: %v\n", book.Name, err)
-            continue
+            return nil, fmt.Errorf("failed to create category %s: %v", name, err)
+        }
+        categoryIDs[name] = category.ID
+    }
+
+    return categoryIDs, nil
+}
+
+// populateBookChannel creates (or reuses) a book's channel and posts any
+// messages not yet recorded in guildStore, so a retry after a crash or
+// rate limit resumes instead of duplicating channels or messages.
+func populateBookChannel(s *discordgo.Session, guildID string, book BibleBook, everyoneRoleID, parentID string, existingByName map[string]*discordgo.Channel, tracker *crossRefTracker) error {
+    channelName := strings.ToLower(strings.ReplaceAll(book.Name, " ", "-"))
+    if len(channelName) > 100 {
+        channelName = channelName[:100]
+    }
+
+    channel, ok := existingByName[channelName]
+    if !ok {
+        var err error
+        channel, err = s.GuildChannelCreateComplex(guildID, discordgo.GuildChannelCreateData{
+            Name:     channelName,
+            Type:     discordgo.ChannelTypeGuildText,
+            ParentID: parentID,
+        })
+        if err != nil {
+            return fmt.Errorf("failed to create channel: %v", err)
         }
 
         err = s.ChannelPermissionSet(channel.ID, everyoneRoleID, discordgo.PermissionOverwriteTypeRole,
@@ -150,34 +242,314 @@ func setupServer(s *discordgo.Session, guildID string, books []BibleBook) error
         if err != nil {
             fmt.Printf("Warning: failed to set permissions for %s: %v\n", book.Name, err)
         }
+    }
 
-        bookText := formatBook(book)
-        messages := splitMessage(bookText)
-        for _, msg := range messages {
-            _, err := s.ChannelMessageSend(channel.ID, msg)
-            if err != nil {
-                fmt.Printf("Warning: failed to send message to %s: %v\n", book.Name, err)
-            }
+    if channelProgress(guildStore, guildID, book.Name) > 0 {
+        return nil
+    }
+
+    pages := bookPages(book)
+    sent, err := sendComplexWithRateLimitRetry(s, channel.ID, &discordgo.MessageSend{
+        Embed:      bookPageEmbed(book, pages, 0),
+        Components: bookPageComponents(book.Name, 0, len(pages)),
+    })
+    if err != nil {
+        return fmt.Errorf("failed to send book page: %v", err)
+    }
+    tracker.recordMessage(book.Name, channel.ID, sent.ID, pages[0])
+
+    if err := setChannelProgress(guildStore, guildID, book.Name, 1); err != nil {
+        fmt.Printf("Warning: failed to persist progress for %s: %v\n", book.Name, err)
+    }
+
+    return nil
+}
+
+// sendWithRateLimitRetry posts a message, retrying on Discord's 429s
+// using the Retry-After duration discordgo parses onto the error. It
+// opts this call out of discordgo's own built-in rate-limit retry (via
+// WithRetryOnRatelimit(false)) so it sees the *discordgo.RateLimitError
+// itself instead of discordgo silently blocking inside the request;
+// every other REST call on the session keeps the library's default
+// retry behavior.
+func sendWithRateLimitRetry(s *discordgo.Session, channelID, content string) (*discordgo.Message, error) {
+    for {
+        msg, err := s.ChannelMessageSend(channelID, content, discordgo.WithRetryOnRatelimit(false))
+        if err == nil {
+            return msg, nil
+        }
+
+        var rlErr *discordgo.RateLimitError
+        if errors.As(err, &rlErr) {
+            time.Sleep(rlErr.RateLimit.RetryAfter)
+            continue
+        }
+        return nil, err
+    }
+}
+
+// sendComplexWithRateLimitRetry is sendWithRateLimitRetry for embed/component messages.
+func sendComplexWithRateLimitRetry(s *discordgo.Session, channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+    for {
+        msg, err := s.ChannelMessageSendComplex(channelID, data, discordgo.WithRetryOnRatelimit(false))
+        if err == nil {
+            return msg, nil
         }
+
+        var rlErr *discordgo.RateLimitError
+        if errors.As(err, &rlErr) {
+            time.Sleep(rlErr.RateLimit.RetryAfter)
+            continue
+        }
+        return nil, err
     }
+}
 
+var commands = []*discordgo.ApplicationCommand{
+    {
+        Name:        "verse",
+        Description: "Look up a single verse",
+        Options: []*discordgo.ApplicationCommandOption{
+            {Type: discordgo.ApplicationCommandOptionString, Name: "book", Description: "Book name", Required: true},
+            {Type: discordgo.ApplicationCommandOptionInteger, Name: "chapter", Description: "Chapter number", Required: true},
+            {Type: discordgo.ApplicationCommandOptionInteger, Name: "verse", Description: "Verse number", Required: true},
+            {Type: discordgo.ApplicationCommandOptionString, Name: "translation", Description: "Translation to use, e.g. NET", Required: false},
+        },
+    },
+    {
+        Name:        "passage",
+        Description: "Look up a range of verses",
+        Options: []*discordgo.ApplicationCommandOption{
+            {Type: discordgo.ApplicationCommandOptionString, Name: "book", Description: "Book name", Required: true},
+            {Type: discordgo.ApplicationCommandOptionInteger, Name: "chapter", Description: "Chapter number", Required: true},
+            {Type: discordgo.ApplicationCommandOptionInteger, Name: "start", Description: "Starting verse", Required: true},
+            {Type: discordgo.ApplicationCommandOptionInteger, Name: "end", Description: "Ending verse", Required: true},
+            {Type: discordgo.ApplicationCommandOptionString, Name: "translation", Description: "Translation to use, e.g. NET", Required: false},
+        },
+    },
+    {
+        Name:        "search",
+        Description: "Search the Bible for a word or phrase",
+        Options: []*discordgo.ApplicationCommandOption{
+            {Type: discordgo.ApplicationCommandOptionString, Name: "query", Description: "Text to search for", Required: true},
+            {Type: discordgo.ApplicationCommandOptionString, Name: "translation", Description: "Translation to use, e.g. NET", Required: false},
+        },
+    },
+    {
+        Name:        "random",
+        Description: "Get a random verse",
+        Options: []*discordgo.ApplicationCommandOption{
+            {Type: discordgo.ApplicationCommandOptionString, Name: "translation", Description: "Translation to use, e.g. NET", Required: false},
+        },
+    },
+    {
+        Name:        "translation",
+        Description: "Set this server's default Bible translation",
+        Options: []*discordgo.ApplicationCommandOption{
+            {Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "Translation name, e.g. NET", Required: true},
+        },
+    },
+    {
+        Name:        "plan",
+        Description: "Manage the daily reading plan",
+        Options: []*discordgo.ApplicationCommandOption{
+            {
+                Type:        discordgo.ApplicationCommandOptionSubCommand,
+                Name:        "start",
+                Description: "Start a reading plan",
+                Options: []*discordgo.ApplicationCommandOption{
+                    {Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "Plan name, e.g. mcheyne", Required: true},
+                    {Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "Channel to post daily readings to", Required: true},
+                    {Type: discordgo.ApplicationCommandOptionString, Name: "time", Description: "Time of day to post, e.g. 07:00", Required: true},
+                    {Type: discordgo.ApplicationCommandOptionString, Name: "tz", Description: "IANA timezone, e.g. America/New_York", Required: true},
+                },
+            },
+            {Type: discordgo.ApplicationCommandOptionSubCommand, Name: "pause", Description: "Pause the reading plan"},
+            {Type: discordgo.ApplicationCommandOptionSubCommand, Name: "resume", Description: "Resume the reading plan"},
+            {Type: discordgo.ApplicationCommandOptionSubCommand, Name: "skip", Description: "Skip today's reading"},
+        },
+    },
+}
+
+func registerCommands(s *discordgo.Session) error {
+    for _, cmd := range commands {
+        if _, err := s.ApplicationCommandCreate(s.State.User.ID, "", cmd); err != nil {
+            return fmt.Errorf("failed to register command %s: %v", cmd.Name, err)
+        }
+    }
     return nil
 }
 
+func onInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+    if i.Type == discordgo.InteractionMessageComponent {
+        onMessageComponentInteraction(s, i)
+        return
+    }
+    if i.Type == discordgo.InteractionModalSubmit {
+        onModalSubmitInteraction(s, i)
+        return
+    }
+    if i.Type != discordgo.InteractionApplicationCommand {
+        return
+    }
+
+    data := i.ApplicationCommandData()
+
+    if data.Name == "plan" {
+        respondToInteraction(s, i, handlePlanCommand(s, i))
+        return
+    }
+
+    opts := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+    for _, opt := range data.Options {
+        opts[opt.Name] = opt
+    }
+
+    translation := guildTranslation(guildStore, i.GuildID, defaultTranslation)
+    if opt, ok := opts["translation"]; ok {
+        translation = opt.StringValue()
+    }
+
+    var content string
+    switch data.Name {
+    case "verse":
+        content = handleVerseCommand(opts, translation)
+    case "passage":
+        content = handlePassageCommand(opts, translation)
+    case "search":
+        content = handleSearchCommand(opts, translation)
+    case "random":
+        content = handleRandomCommand(translation)
+    case "translation":
+        content = handleTranslationCommand(opts, i.GuildID)
+    default:
+        return
+    }
+
+    respondToInteraction(s, i, content)
+}
+
+func respondToInteraction(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+    err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+        Type: discordgo.InteractionResponseChannelMessageWithSource,
+        Data: &discordgo.InteractionResponseData{Content: content},
+    })
+    if err != nil {
+        fmt.Printf("Warning: failed to respond to interaction %s: %v\n", i.ApplicationCommandData().Name, err)
+    }
+}
+
+func handleVerseCommand(opts map[string]*discordgo.ApplicationCommandInteractionDataOption, translation string) string {
+    bookName := opts["book"].StringValue()
+    chapter := int(opts["chapter"].IntValue())
+    verseNum := int(opts["verse"].IntValue())
+
+    book, ok := findBook(translations[translation], bookName)
+    if !ok {
+        return fmt.Sprintf("Could not find book %q", bookName)
+    }
+
+    for _, v := range book.Verses {
+        if v.Chapter == chapter && v.Verse == verseNum {
+            return fmt.Sprintf("**%s %d:%d** %s", book.Name, v.Chapter, v.Verse, v.Text)
+        }
+    }
+    return fmt.Sprintf("Could not find %s %d:%d", book.Name, chapter, verseNum)
+}
+
+func handlePassageCommand(opts map[string]*discordgo.ApplicationCommandInteractionDataOption, translation string) string {
+    bookName := opts["book"].StringValue()
+    chapter := int(opts["chapter"].IntValue())
+    start := int(opts["start"].IntValue())
+    end := int(opts["end"].IntValue())
+
+    book, ok := findBook(translations[translation], bookName)
+    if !ok {
+        return fmt.Sprintf("Could not find book %q", bookName)
+    }
+
+    var builder strings.Builder
+    fmt.Fprintf(&builder, "**%s %d:%d-%d**\n", book.Name, chapter, start, end)
+    for _, v := range book.Verses {
+        if v.Chapter == chapter && v.Verse >= start && v.Verse <= end {
+            fmt.Fprintf(&builder, "%d %s\n", v.Verse, v.Text)
+        }
+    }
+    if builder.Len() == 0 {
+        return fmt.Sprintf("Could not find %s %d:%d-%d", book.Name, chapter, start, end)
+    }
+    return builder.String()
+}
+
+func handleSearchCommand(opts map[string]*discordgo.ApplicationCommandInteractionDataOption, translation string) string {
+    query := strings.ToLower(opts["query"].StringValue())
+
+    var hits []*Verse
+    seen := make(map[*Verse]bool)
+    index := searchIndexes[translation]
+    for _, word := range strings.Fields(query) {
+        for _, verse := range index[word] {
+            if !seen[verse] {
+                seen[verse] = true
+                hits = append(hits, verse)
+            }
+        }
+    }
+
+    if len(hits) == 0 {
+        return fmt.Sprintf("No verses found for %q", opts["query"].StringValue())
+    }
+
+    const maxResults = 5
+    var builder strings.Builder
+    for i, v := range hits {
+        if i >= maxResults {
+            fmt.Fprintf(&builder, "...and %d more", len(hits)-maxResults)
+            break
+        }
+        fmt.Fprintf(&builder, "**%s %d:%d** %s\n", v.BookName, v.Chapter, v.Verse, v.Text)
+    }
+    return builder.String()
+}
+
+func handleRandomCommand(translation string) string {
+    books := translations[translation]
+    if len(books) == 0 {
+        return "No Bible data loaded"
+    }
+    book := books[rand.Intn(len(books))]
+    if len(book.Verses) == 0 {
+        return "No Bible data loaded"
+    }
+    verse := book.Verses[rand.Intn(len(book.Verses))]
+    return fmt.Sprintf("**%s %d:%d** %s", verse.BookName, verse.Chapter, verse.Verse, verse.Text)
+}
+
+func handleTranslationCommand(opts map[string]*discordgo.ApplicationCommandInteractionDataOption, guildID string) string {
+    name := opts["name"].StringValue()
+    if _, ok := translations[name]; !ok {
+        return fmt.Sprintf("Unknown translation %q", name)
+    }
+
+    if err := setGuildTranslation(guildStore, guildID, name); err != nil {
+        return fmt.Sprintf("Failed to save translation preference: %v", err)
+    }
+    return fmt.Sprintf("Default translation set to %s", name)
+}
+
 func onReady(s *discordgo.Session, event *discordgo.Ready) {
     fmt.Println("Bot is ready!")
+
+    if err := registerCommands(s); err != nil {
+        fmt.Printf("Error registering commands: %v\n", err)
+    }
 }
 
 func onGuildCreate(s *discordgo.Session, event *discordgo.GuildCreate) {
     fmt.Printf("Joined guild: %s (%s)\n", event.Guild.Name, event.Guild.ID)
 
-    books, err := loadBibleData()
-    if err != nil {
-        fmt.Printf("Error loading Bible data: %v\n", err)
-        return
-    }
-
-    err = setupServer(s, event.Guild.ID, books)
+    translation := guildTranslation(guildStore, event.Guild.ID, defaultTranslation)
+    err := setupServer(s, event.Guild.ID, translations[translation])
     if err != nil {
         fmt.Printf("Error setting up server: %v\n", err)
     } else {
@@ -187,6 +559,26 @@ func onGuildCreate(s *discordgo.Session, event *discordgo.GuildCreate) {
 
 func main() {
     config := loadConfig()
+    defaultTranslation = config.DefaultTranslation
+
+    loaded, err := loadTranslations(config)
+    if err != nil {
+        fmt.Printf("Error loading Bible data: %v\n", err)
+        return
+    }
+    translations = loaded
+
+    searchIndexes = make(map[string]map[string][]*Verse, len(translations))
+    for name, books := range translations {
+        searchIndexes[name] = buildSearchIndex(books)
+    }
+
+    guildStore, err = openGuildStore(guildStorePath)
+    if err != nil {
+        fmt.Printf("Error opening guild store: %v\n", err)
+        return
+    }
+    defer guildStore.Close()
 
     dg, err := discordgo.New("Bot " + config.Token)
     if err != nil {
@@ -196,6 +588,7 @@ func main() {
 
     dg.AddHandler(onReady)
     dg.AddHandler(onGuildCreate)
+    dg.AddHandler(onInteractionCreate)
 
     err = dg.Open()
     if err != nil {
@@ -203,6 +596,8 @@ func main() {
         return
     }
 
+    startPlanScheduler(dg)
+
     fmt.Println("Bot is running. Press CTRL+C to exit.")
 
     select {}
@@ -0,0 +1,212 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/bwmarrin/discordgo"
+)
+
+// embedPageSize stays under Discord's 4096-character embed description
+// limit (and well under the 6000-character total-per-embed limit, since
+// a book page only uses a title, description and footer).
+const embedPageSize = 4096
+
+// splitTextByLines breaks text into chunks of at most maxLength
+// characters, preferring to break on a newline so a verse is never cut
+// in half.
+func splitTextByLines(text string, maxLength int) []string {
+    var chunks []string
+    for len(text) > 0 {
+        if len(text) <= maxLength {
+            chunks = append(chunks, text)
+            break
+        }
+
+        lastNewline := strings.LastIndex(text[:maxLength], "\n")
+        if lastNewline == -1 {
+            lastNewline = maxLength
+        }
+        chunks = append(chunks, text[:lastNewline])
+        text = text[lastNewline:]
+    }
+    return chunks
+}
+
+// bookPages splits a book into embed-sized pages for the paginated
+// reader, in place of dumping the whole book as raw messages.
+func bookPages(book BibleBook) []string {
+    return splitTextByLines(formatBook(book), embedPageSize)
+}
+
+func bookPageEmbed(book BibleBook, pages []string, page int) *discordgo.MessageEmbed {
+    return &discordgo.MessageEmbed{
+        Title:       book.Name,
+        Description: pages[page],
+        Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Page %d/%d", page+1, len(pages))},
+    }
+}
+
+func bookPageComponents(bookName string, page, total int) []discordgo.MessageComponent {
+    return []discordgo.MessageComponent{
+        discordgo.ActionsRow{
+            Components: []discordgo.MessageComponent{
+                discordgo.Button{
+                    Label:    "Prev",
+                    Style:    discordgo.SecondaryButton,
+                    CustomID: fmt.Sprintf("bible:%s:prev:%d", bookName, page),
+                    Disabled: page == 0,
+                },
+                discordgo.Button{
+                    Label:    "Next",
+                    Style:    discordgo.SecondaryButton,
+                    CustomID: fmt.Sprintf("bible:%s:next:%d", bookName, page),
+                    Disabled: page >= total-1,
+                },
+                discordgo.Button{
+                    Label:    "Jump",
+                    Style:    discordgo.SecondaryButton,
+                    CustomID: fmt.Sprintf("bible:%s:jump:%d", bookName, page),
+                    Disabled: total <= 1,
+                },
+            },
+        },
+    }
+}
+
+// jumpPageInputID is the custom ID of the page-number text input inside
+// the Jump modal, so onModalSubmitInteraction knows which field to read.
+const jumpPageInputID = "page"
+
+// bookJumpModal builds the modal shown when a reader clicks Jump, asking
+// for the 1-indexed page number to go to.
+func bookJumpModal(bookName string, page, total int) *discordgo.InteractionResponseData {
+    return &discordgo.InteractionResponseData{
+        CustomID: fmt.Sprintf("bible:%s:jumpmodal:%d", bookName, page),
+        Title:    fmt.Sprintf("Jump to page (1-%d)", total),
+        Components: []discordgo.MessageComponent{
+            discordgo.ActionsRow{
+                Components: []discordgo.MessageComponent{
+                    discordgo.TextInput{
+                        CustomID:    jumpPageInputID,
+                        Label:       "Page number",
+                        Style:       discordgo.TextInputShort,
+                        Placeholder: fmt.Sprintf("1-%d", total),
+                        Required:    true,
+                    },
+                },
+            },
+        },
+    }
+}
+
+// onMessageComponentInteraction handles Prev/Next/Jump button clicks from
+// bookPageComponents. Prev/Next edit the message in place to show the new
+// page; Jump opens a modal asking for a page number.
+func onMessageComponentInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+    customID := i.MessageComponentData().CustomID
+    parts := strings.SplitN(customID, ":", 4)
+    if len(parts) != 4 || parts[0] != "bible" {
+        return
+    }
+    bookName, direction, fromPage := parts[1], parts[2], parts[3]
+
+    var page int
+    if _, err := fmt.Sscanf(fromPage, "%d", &page); err != nil {
+        return
+    }
+
+    translation := guildTranslation(guildStore, i.GuildID, defaultTranslation)
+    book, ok := findBook(translations[translation], bookName)
+    if !ok {
+        return
+    }
+    pages := bookPages(book)
+
+    if direction == "jump" {
+        err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+            Type: discordgo.InteractionResponseModal,
+            Data: bookJumpModal(bookName, page, len(pages)),
+        })
+        if err != nil {
+            fmt.Printf("Warning: failed to open jump modal for %s: %v\n", bookName, err)
+        }
+        return
+    }
+
+    if direction == "next" {
+        page++
+    } else {
+        page--
+    }
+    if page < 0 || page >= len(pages) {
+        return
+    }
+
+    err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+        Type: discordgo.InteractionResponseUpdateMessage,
+        Data: &discordgo.InteractionResponseData{
+            Embeds:     []*discordgo.MessageEmbed{bookPageEmbed(book, pages, page)},
+            Components: bookPageComponents(bookName, page, len(pages)),
+        },
+    })
+    if err != nil {
+        fmt.Printf("Warning: failed to update page for %s: %v\n", bookName, err)
+    }
+}
+
+// onModalSubmitInteraction handles the page-number submission from the
+// Jump modal opened by onMessageComponentInteraction, editing the
+// originating message to show the requested page. Discord carries the
+// originating message through on a modal opened from a component
+// interaction, so i.Message is the paginated book message being jumped
+// from.
+func onModalSubmitInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+    customID := i.ModalSubmitData().CustomID
+    parts := strings.SplitN(customID, ":", 4)
+    if len(parts) != 4 || parts[0] != "bible" || parts[2] != "jumpmodal" {
+        return
+    }
+    bookName := parts[1]
+
+    var input string
+    for _, row := range i.ModalSubmitData().Components {
+        actionRow, ok := row.(*discordgo.ActionsRow)
+        if !ok {
+            continue
+        }
+        for _, comp := range actionRow.Components {
+            if textInput, ok := comp.(*discordgo.TextInput); ok && textInput.CustomID == jumpPageInputID {
+                input = textInput.Value
+            }
+        }
+    }
+
+    var requestedPage int
+    if _, err := fmt.Sscanf(input, "%d", &requestedPage); err != nil {
+        return
+    }
+    page := requestedPage - 1 // the modal asks for a 1-indexed page number
+
+    translation := guildTranslation(guildStore, i.GuildID, defaultTranslation)
+    book, ok := findBook(translations[translation], bookName)
+    if !ok {
+        return
+    }
+
+    pages := bookPages(book)
+    if page < 0 || page >= len(pages) {
+        return
+    }
+
+    err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+        Type: discordgo.InteractionResponseUpdateMessage,
+        Data: &discordgo.InteractionResponseData{
+            Embeds:     []*discordgo.MessageEmbed{bookPageEmbed(book, pages, page)},
+            Components: bookPageComponents(bookName, page, len(pages)),
+        },
+    })
+    if err != nil {
+        fmt.Printf("Warning: failed to jump to page for %s: %v\n", bookName, err)
+    }
+}
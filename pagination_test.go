@@ -0,0 +1,41 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestSplitTextByLinesRespectsMaxLength(t *testing.T) {
+    text := "1 one\n2 two\n3 three\n4 four\n"
+    chunks := splitTextByLines(text, 12)
+    for _, c := range chunks {
+        if len(c) > 12 {
+            t.Errorf("chunk %q exceeds max length 12", c)
+        }
+    }
+    if got := strings.Join(chunks, ""); got != text {
+        t.Errorf("chunks do not reconstruct the original text: got %q, want %q", got, text)
+    }
+}
+
+func TestSplitTextByLinesFallsBackToHardCutWithoutNewline(t *testing.T) {
+    text := "abcdefghij"
+    chunks := splitTextByLines(text, 4)
+    want := []string{"abcd", "efgh", "ij"}
+    if len(chunks) != len(want) {
+        t.Fatalf("got %v, want %v", chunks, want)
+    }
+    for i := range want {
+        if chunks[i] != want[i] {
+            t.Errorf("chunk %d = %q, want %q", i, chunks[i], want[i])
+        }
+    }
+}
+
+func TestSplitTextByLinesShortTextIsSingleChunk(t *testing.T) {
+    text := "short"
+    chunks := splitTextByLines(text, 100)
+    if len(chunks) != 1 || chunks[0] != text {
+        t.Errorf("splitTextByLines(%q, 100) = %v, want a single chunk %q", text, chunks, text)
+    }
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/BurntSushi/toml"
+    "github.com/bwmarrin/discordgo"
+    bolt "go.etcd.io/bbolt"
+)
+
+// PlanReading is one passage within a single day's reading.
+type PlanReading struct {
+    Book    string `toml:"book"`
+    Chapter int    `toml:"chapter"`
+    Start   int    `toml:"start"`
+    End     int    `toml:"end"`
+}
+
+// PlanDay is everything to be read on a given day of a plan.
+type PlanDay struct {
+    Day      int           `toml:"day"`
+    Readings []PlanReading `toml:"reading"`
+}
+
+// PlanDefinition is a full reading plan (M'Cheyne, chronological, etc.),
+// loaded from plans/<name>.toml.
+type PlanDefinition struct {
+    Name string
+    Days []PlanDay `toml:"day"`
+}
+
+func loadPlanDefinition(name string) (PlanDefinition, error) {
+    path := fmt.Sprintf("plans/%s.toml", name)
+    var def PlanDefinition
+    if _, err := toml.DecodeFile(path, &def); err != nil {
+        return PlanDefinition{}, fmt.Errorf("failed to load plan %s: %v", name, err)
+    }
+    def.Name = name
+    return def, nil
+}
+
+// planSubscription is a guild's progress through a reading plan.
+type planSubscription struct {
+    Plan       string `json:"plan"`
+    ChannelID  string `json:"channel_id"`
+    Time       string `json:"time"`     // "07:00", local to Timezone
+    Timezone   string `json:"timezone"` // IANA zone, e.g. "America/New_York"
+    Day        int    `json:"day"`      // next day to post, 1-based
+    Paused     bool   `json:"paused"`
+    LastPosted string `json:"last_posted"` // "2006-01-02", to avoid double-posting within the same day
+}
+
+func getPlanSubscription(db *bolt.DB, guildID string) (planSubscription, bool) {
+    var sub planSubscription
+    found := false
+    db.View(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket([]byte(readingPlansBucket))
+        value := bucket.Get([]byte(guildID))
+        if value == nil {
+            return nil
+        }
+        if err := json.Unmarshal(value, &sub); err != nil {
+            return err
+        }
+        found = true
+        return nil
+    })
+    return sub, found
+}
+
+func setPlanSubscription(db *bolt.DB, guildID string, sub planSubscription) error {
+    value, err := json.Marshal(sub)
+    if err != nil {
+        return fmt.Errorf("failed to encode plan subscription: %v", err)
+    }
+    return db.Update(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket([]byte(readingPlansBucket))
+        return bucket.Put([]byte(guildID), value)
+    })
+}
+
+// planSchedulerInterval matches the request's "wakes hourly" cadence.
+const planSchedulerInterval = time.Hour
+
+// startPlanScheduler polls every guild's reading plan subscription once
+// per tick and posts the day's passage to any that are due.
+func startPlanScheduler(s *discordgo.Session) {
+    ticker := time.NewTicker(planSchedulerInterval)
+    go func() {
+        for range ticker.C {
+            checkDuePlans(s)
+        }
+    }()
+}
+
+func checkDuePlans(s *discordgo.Session) {
+    var guildIDs [][]byte
+    guildStore.View(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket([]byte(readingPlansBucket))
+        return bucket.ForEach(func(k, v []byte) error {
+            guildIDs = append(guildIDs, append([]byte(nil), k...))
+            return nil
+        })
+    })
+
+    for _, guildID := range guildIDs {
+        sub, ok := getPlanSubscription(guildStore, string(guildID))
+        if !ok || sub.Paused {
+            continue
+        }
+        if err := postDueReading(s, string(guildID), sub); err != nil {
+            fmt.Printf("Warning: failed to post reading plan for guild %s: %v\n", guildID, err)
+        }
+    }
+}
+
+func postDueReading(s *discordgo.Session, guildID string, sub planSubscription) error {
+    loc, err := time.LoadLocation(sub.Timezone)
+    if err != nil {
+        return fmt.Errorf("invalid timezone %s: %v", sub.Timezone, err)
+    }
+
+    now := time.Now().In(loc)
+    today := now.Format("2006-01-02")
+    if sub.LastPosted == today {
+        return nil
+    }
+
+    hour, _, err := parsePlanTime(sub.Time)
+    if err != nil {
+        return err
+    }
+    if now.Hour() != hour {
+        return nil
+    }
+
+    return advancePlan(s, guildID, sub, today)
+}
+
+func parsePlanTime(value string) (hour, minute int, err error) {
+    parts := strings.Split(value, ":")
+    if len(parts) != 2 {
+        return 0, 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+    }
+    hour, err = strconv.Atoi(parts[0])
+    if err != nil {
+        return 0, 0, fmt.Errorf("invalid time %q: %v", value, err)
+    }
+    minute, err = strconv.Atoi(parts[1])
+    if err != nil {
+        return 0, 0, fmt.Errorf("invalid time %q: %v", value, err)
+    }
+    return hour, minute, nil
+}
+
+// advancePlan posts sub's current day and persists the incremented
+// subscription; it is also used by /plan skip to move forward silently.
+func advancePlan(s *discordgo.Session, guildID string, sub planSubscription, today string) error {
+    def, err := loadPlanDefinition(sub.Plan)
+    if err != nil {
+        return err
+    }
+
+    var day *PlanDay
+    for i := range def.Days {
+        if def.Days[i].Day == sub.Day {
+            day = &def.Days[i]
+            break
+        }
+    }
+    if day == nil {
+        return fmt.Errorf("plan %s has no day %d", sub.Plan, sub.Day)
+    }
+
+    translation := guildTranslation(guildStore, guildID, defaultTranslation)
+    text := formatPlanDay(*day, translations[translation])
+    for _, msg := range splitMessage(text) {
+        if _, err := sendWithRateLimitRetry(s, sub.ChannelID, msg); err != nil {
+            return fmt.Errorf("failed to post reading: %v", err)
+        }
+    }
+
+    sub.Day++
+    sub.LastPosted = today
+    return setPlanSubscription(guildStore, guildID, sub)
+}
+
+func formatPlanDay(day PlanDay, books []BibleBook) string {
+    var builder strings.Builder
+    for _, reading := range day.Readings {
+        book, ok := findBook(books, reading.Book)
+        if !ok {
+            continue
+        }
+        fmt.Fprintf(&builder, "**%s %d:%d-%d**\n", book.Name, reading.Chapter, reading.Start, reading.End)
+        for _, v := range book.Verses {
+            if v.Chapter == reading.Chapter && v.Verse >= reading.Start && v.Verse <= reading.End {
+                fmt.Fprintf(&builder, "%d %s\n", v.Verse, v.Text)
+            }
+        }
+        builder.WriteString("\n")
+    }
+    return builder.String()
+}
+
+func handlePlanCommand(s *discordgo.Session, i *discordgo.InteractionCreate) string {
+    data := i.ApplicationCommandData()
+    if len(data.Options) == 0 {
+        return "Usage: /plan start|pause|resume|skip"
+    }
+    sub := data.Options[0]
+
+    opts := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(sub.Options))
+    for _, opt := range sub.Options {
+        opts[opt.Name] = opt
+    }
+
+    switch sub.Name {
+    case "start":
+        return handlePlanStart(s, i.GuildID, opts)
+    case "pause":
+        return handlePlanPause(i.GuildID)
+    case "resume":
+        return handlePlanResume(i.GuildID)
+    case "skip":
+        return handlePlanSkip(i.GuildID)
+    default:
+        return "Unknown /plan subcommand"
+    }
+}
+
+func handlePlanStart(s *discordgo.Session, guildID string, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) string {
+    name := opts["name"].StringValue()
+    if _, err := loadPlanDefinition(name); err != nil {
+        return fmt.Sprintf("Unknown reading plan %q", name)
+    }
+
+    channel := opts["channel"].ChannelValue(s)
+    planTime := opts["time"].StringValue()
+    if _, _, err := parsePlanTime(planTime); err != nil {
+        return err.Error()
+    }
+    tz := opts["tz"].StringValue()
+    if _, err := time.LoadLocation(tz); err != nil {
+        return fmt.Sprintf("Unknown timezone %q", tz)
+    }
+
+    sub := planSubscription{
+        Plan:      name,
+        ChannelID: channel.ID,
+        Time:      planTime,
+        Timezone:  tz,
+        Day:       1,
+    }
+    if err := setPlanSubscription(guildStore, guildID, sub); err != nil {
+        return fmt.Sprintf("Failed to start plan: %v", err)
+    }
+    return fmt.Sprintf("Started plan %q, posting daily at %s %s to <#%s>", name, planTime, tz, channel.ID)
+}
+
+func handlePlanPause(guildID string) string {
+    sub, ok := getPlanSubscription(guildStore, guildID)
+    if !ok {
+        return "No reading plan is active for this server"
+    }
+    sub.Paused = true
+    if err := setPlanSubscription(guildStore, guildID, sub); err != nil {
+        return fmt.Sprintf("Failed to pause plan: %v", err)
+    }
+    return "Reading plan paused"
+}
+
+func handlePlanResume(guildID string) string {
+    sub, ok := getPlanSubscription(guildStore, guildID)
+    if !ok {
+        return "No reading plan is active for this server"
+    }
+    sub.Paused = false
+    if err := setPlanSubscription(guildStore, guildID, sub); err != nil {
+        return fmt.Sprintf("Failed to resume plan: %v", err)
+    }
+    return "Reading plan resumed"
+}
+
+func handlePlanSkip(guildID string) string {
+    sub, ok := getPlanSubscription(guildStore, guildID)
+    if !ok {
+        return "No reading plan is active for this server"
+    }
+    sub.Day++
+    if err := setPlanSubscription(guildStore, guildID, sub); err != nil {
+        return fmt.Sprintf("Failed to skip day: %v", err)
+    }
+    return fmt.Sprintf("Skipped to day %d", sub.Day)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+    "fmt"
+    "testing"
+    "time"
+)
+
+func TestParsePlanTime(t *testing.T) {
+    cases := []struct {
+        in         string
+        wantHour   int
+        wantMinute int
+        wantErr    bool
+    }{
+        {"07:00", 7, 0, false},
+        {"23:59", 23, 59, false},
+        {"7:00", 7, 0, false},
+        {"0700", 0, 0, true},
+        {"07:ab", 0, 0, true},
+        {"", 0, 0, true},
+    }
+    for _, c := range cases {
+        hour, minute, err := parsePlanTime(c.in)
+        if c.wantErr {
+            if err == nil {
+                t.Errorf("parsePlanTime(%q) expected an error, got none", c.in)
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("parsePlanTime(%q) unexpected error: %v", c.in, err)
+            continue
+        }
+        if hour != c.wantHour || minute != c.wantMinute {
+            t.Errorf("parsePlanTime(%q) = %d:%d, want %d:%d", c.in, hour, minute, c.wantHour, c.wantMinute)
+        }
+    }
+}
+
+func TestPostDueReadingSkipsWhenAlreadyPostedToday(t *testing.T) {
+    today := time.Now().UTC().Format("2006-01-02")
+    sub := planSubscription{Timezone: "UTC", Time: "00:00", LastPosted: today}
+    if err := postDueReading(nil, "guild1", sub); err != nil {
+        t.Fatalf("postDueReading returned error: %v", err)
+    }
+}
+
+func TestPostDueReadingSkipsWhenNotDueHour(t *testing.T) {
+    offHour := (time.Now().UTC().Hour() + 1) % 24
+    sub := planSubscription{Timezone: "UTC", Time: fmt.Sprintf("%02d:00", offHour)}
+    if err := postDueReading(nil, "guild1", sub); err != nil {
+        t.Fatalf("postDueReading returned error: %v", err)
+    }
+}
+
+func TestPostDueReadingRejectsInvalidTimezone(t *testing.T) {
+    sub := planSubscription{Timezone: "Not/AZone", Time: "07:00"}
+    if err := postDueReading(nil, "guild1", sub); err == nil {
+        t.Fatal("expected an error for an invalid timezone")
+    }
+}
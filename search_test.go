@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestBuildSearchIndexTokenizesAndLowercases(t *testing.T) {
+    books := []BibleBook{
+        {
+            Name: "John",
+            Verses: []Verse{
+                {BookName: "John", Chapter: 3, Verse: 16, Text: "For God so loved the World!"},
+            },
+        },
+    }
+    index := buildSearchIndex(books)
+
+    if _, ok := index["world"]; !ok {
+        t.Fatalf("expected lowercased, punctuation-stripped token %q in index", "world")
+    }
+    if _, ok := index["World!"]; ok {
+        t.Errorf("index should not retain original casing/punctuation as a key")
+    }
+    if got := index["world"]; len(got) != 1 || got[0].Verse != 16 {
+        t.Errorf("unexpected verses indexed under %q: %+v", "world", got)
+    }
+}
+
+func TestBuildSearchIndexSkipsEmptyTokens(t *testing.T) {
+    books := []BibleBook{
+        {
+            Name: "Psalms",
+            Verses: []Verse{
+                {BookName: "Psalms", Chapter: 1, Verse: 1, Text: "...  --  "},
+            },
+        },
+    }
+    index := buildSearchIndex(books)
+    if _, ok := index[""]; ok {
+        t.Errorf("index should not contain an empty-string token")
+    }
+}